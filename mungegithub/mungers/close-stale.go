@@ -17,8 +17,12 @@ limitations under the License.
 package mungers
 
 import (
+	"encoding/json"
 	"fmt"
+	"path/filepath"
 	"regexp"
+	"strconv"
+	"strings"
 	"time"
 
 	"k8s.io/test-infra/mungegithub/features"
@@ -26,16 +30,34 @@ import (
 	"k8s.io/test-infra/mungegithub/mungers/mungerutil"
 
 	githubapi "github.com/google/go-github/github"
+	"github.com/golang/glog"
+	"github.com/prometheus/client_golang/prometheus"
 	"github.com/spf13/cobra"
 )
 
 const (
-	day            = time.Hour * 24
-	keepOpenLabel  = "keep-open"
-	kindFlakeLabel = "kind/flake"
-	stalePeriod    = 90 * day // Close the PR/Issue if no human interaction for `stalePeriod`
-	startWarning   = 60 * day
-	remindWarning  = 30 * day
+	day = time.Hour * 24
+
+	// defaultKeepOpenLabel is the label that, by itself, keeps a PR/Issue
+	// open forever; overridable/extendable via --stale-keep-open-label.
+	defaultKeepOpenLabel = "keep-open"
+	// defaultIncludeLabel is the only label (besides being a PR) that makes
+	// an Issue eligible for staleness by default; overridable/extendable
+	// via --stale-include-label.
+	defaultIncludeLabel = "kind/flake"
+
+	defaultStalePeriod  = 90 * day // Close the PR/Issue if no human interaction for `stalePeriod`
+	defaultStartWarning = 60 * day
+	remindWarning       = 30 * day
+
+	// escalationGracePeriod is the extra time owners/assignees get to
+	// respond to an escalation ping before the object is actually closed.
+	escalationGracePeriod = 14 * day
+
+	// neverStale is the sentinel value used in --stale-label-period to mark
+	// a label as exempt from staleness entirely (e.g. priority/critical=0).
+	neverStale = time.Duration(0)
+
 	closingComment = `This %s hasn't been active in %s. Closing this %s. Please reopen if you would like to work towards merging this change, if/when the %s is ready for the next round of review.
 
 %s
@@ -44,42 +66,225 @@ You can add 'keep-open' label to prevent this from happening again, or add a com
 
 %s
 You can add 'keep-open' label to prevent this from happening, or add a comment to keep it open another 90 days`
+
+	// closeStaleWarningNotification tags the comment the warningPinger posts,
+	// so it can be found again without parsing the prose above.
+	closeStaleWarningNotification = "close-stale-warning"
+	// closeStaleEscalationNotification tags the comment the escalationPinger
+	// posts when paging owners/assignees as a last resort before closing.
+	closeStaleEscalationNotification = "close-stale-escalation"
+	// closeStaleClosedNotification tags the comment closeObj posts, so a
+	// later "/reopen" comment can be matched back to the close it responds to.
+	closeStaleClosedNotification = "close-stale-closed"
+
+	escalationComment = `This %s hasn't been active in %s and is about to be closed for staleness.
+
+%s
+If this %s is still relevant, please comment or add the 'keep-open' label; otherwise it will be closed in %s (%s).`
 )
 
 var (
-	closingCommentRE = regexp.MustCompile(`This \w hasn't been active in \d+ days?\..*label to prevent this from happening again`)
-	warningCommentRE = regexp.MustCompile(`This \w hasn't been active in \d+ days?\..*be closed in \d+ days?`)
+	staleWarnedTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "stale_warned_total",
+			Help: "Number of times close-stale has warned that a PR/Issue is going stale, by kind and object type",
+		},
+		[]string{"kind", "objtype"},
+	)
+	staleClosedTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "stale_closed_total",
+			Help: "Number of PRs/Issues close-stale has closed for staleness, by kind and object type",
+		},
+		[]string{"kind", "objtype"},
+	)
 )
 
+func init() {
+	prometheus.MustRegister(staleWarnedTotal, staleClosedTotal)
+}
+
+var closingCommentRE = regexp.MustCompile(`This \w hasn't been active in \d+ days?\..*label to prevent this from happening again`)
+
+// labelPeriods is a cobra/pflag Value that accumulates `label=<days>` pairs
+// passed via a repeatable flag (e.g. --stale-label-period=kind/flake=30).
+type labelPeriods map[string]time.Duration
+
+func (l labelPeriods) String() string {
+	parts := make([]string, 0, len(l))
+	for label, period := range l {
+		parts = append(parts, fmt.Sprintf("%s=%d", label, period/day))
+	}
+	return strings.Join(parts, ",")
+}
+
+func (l labelPeriods) Set(value string) error {
+	pieces := strings.SplitN(value, "=", 2)
+	if len(pieces) != 2 {
+		return fmt.Errorf("expected <label>=<days>, got %q", value)
+	}
+	days, err := strconv.Atoi(pieces[1])
+	if err != nil {
+		return fmt.Errorf("invalid day count in %q: %v", value, err)
+	}
+	l[pieces[0]] = time.Duration(days) * day
+	return nil
+}
+
+func (l labelPeriods) Type() string { return "stringToDuration" }
+
 // CloseStale will ask the Bot to close any PR/Issue that didn't
 // have any human interactions in `stalePeriod` duration.
 //
 // This is done by checking both review and issue comments, and by
 // ignoring comments done with a bot name. We also consider re-open on the PR/Issue.
-type CloseStale struct{}
+type CloseStale struct {
+	stalePeriod  time.Duration
+	startWarning time.Duration
+
+	// labelStalePeriods overrides stalePeriod for objects carrying a given
+	// label; a period of neverStale exempts the label from staleness.
+	labelStalePeriods labelPeriods
+
+	// keepOpenLabels, in addition to labelStalePeriods' neverStale entries,
+	// unconditionally keep an object open.
+	keepOpenLabels []string
+
+	// includeLabels are the labels (besides being a PR) that make an Issue
+	// eligible for staleness.
+	includeLabels []string
+
+	// excludeLabels, when present on an object, exempt it from staleness
+	// regardless of includeLabels.
+	excludeLabels []string
+
+	// warningPinger posts (and re-finds) the "this will be closed soon"
+	// comment, pinging again every remindWarning until the object closes or
+	// goes active again.
+	warningPinger Pinger
+
+	// escalateToOwners, when set, pings file/dir owners (for a PR) or
+	// assignees (for an Issue) once the stale period elapses, and gives them
+	// escalationGracePeriod to respond before closeObj actually runs.
+	escalateToOwners bool
+	escalationPinger Pinger
+
+	// closedPinger marks the closing comment, so the companion ReopenStale
+	// munger can match a later human "/reopen" comment back to when (and
+	// whether) the bot closed it.
+	closedPinger Pinger
+
+	// dryRun, when set, makes Munge compute and audit-log its decisions
+	// without posting/deleting comments or opening/closing anything.
+	dryRun bool
+
+	features *features.Features
+}
+
+// staleAudit is the structured, per-object record close-stale logs for
+// every decision it makes -- in both normal and --stale-dry-run mode -- so
+// operators can observe (or safely preview) the effect of tuning
+// stalePeriod/startWarning on a live repo.
+type staleAudit struct {
+	Number       int        `json:"number"`
+	ObjType      string     `json:"objtype"`
+	LastHuman    time.Time  `json:"lastHumanTime"`
+	Decision     string     `json:"decision"`
+	PlannedClose *time.Time `json:"plannedClose,omitempty"`
+	Mentions     []string   `json:"mentions,omitempty"`
+	DryRun       bool       `json:"dryRun"`
+}
+
+// mentionLogins splits a "@user1 @user2" mention string (as produced by
+// mungerutil...Mention().Join()) back into a plain login list for auditing.
+func mentionLogins(mentions string) []string {
+	if mentions == "" {
+		return nil
+	}
+	fields := strings.Fields(mentions)
+	logins := make([]string, len(fields))
+	for i, f := range fields {
+		logins[i] = strings.TrimPrefix(f, "@")
+	}
+	return logins
+}
+
+func (s *CloseStale) audit(obj *github.MungeObject, lastHuman time.Time, decision string, plannedClose *time.Time, mentions string) {
+	record := staleAudit{
+		ObjType:      objType(obj),
+		LastHuman:    lastHuman,
+		Decision:     decision,
+		PlannedClose: plannedClose,
+		Mentions:     mentionLogins(mentions),
+		DryRun:       s.dryRun,
+	}
+	if obj.Issue.Number != nil {
+		record.Number = *obj.Issue.Number
+	}
+
+	raw, err := json.Marshal(record)
+	if err != nil {
+		glog.Errorf("close-stale: failed to marshal audit record: %v", err)
+		return
+	}
+	glog.Infof("close-stale audit: %s", raw)
+}
 
 func init() {
-	s := CloseStale{}
+	s := &CloseStale{}
 	RegisterMungerOrDie(s)
 	RegisterStaleComments(s)
 }
 
 // Name is the name usable in --pr-mungers
-func (CloseStale) Name() string { return "close-stale" }
-
-// RequiredFeatures is a slice of 'features' that must be provided
-func (CloseStale) RequiredFeatures() []string { return []string{} }
+func (*CloseStale) Name() string { return "close-stale" }
+
+// RequiredFeatures is a slice of 'features' that must be provided. RepoInfo
+// (OWNERS lookups) is only needed when --stale-escalate-to-owners is on;
+// existing deployments that don't use escalation shouldn't have to wire it
+// up. This assumes flags are parsed before RequiredFeatures is consulted, as
+// AddFlags already requires for --stale-escalate-to-owners itself to be
+// known by Initialize time.
+func (s *CloseStale) RequiredFeatures() []string {
+	if s.escalateToOwners {
+		return []string{features.RepoInfoName}
+	}
+	return []string{}
+}
 
 // Initialize will initialize the munger
-func (CloseStale) Initialize(config *github.Config, features *features.Features) error {
+func (s *CloseStale) Initialize(config *github.Config, features *features.Features) error {
+	s.features = features
+	s.warningPinger = Pinger{
+		Notification: mungerutil.MungerNotificationName(closeStaleWarningNotification),
+		Period:       remindWarning,
+	}
+	s.escalationPinger = Pinger{
+		Notification: mungerutil.MungerNotificationName(closeStaleEscalationNotification),
+		Period:       escalationGracePeriod,
+	}
+	s.closedPinger = Pinger{
+		Notification: mungerutil.MungerNotificationName(closeStaleClosedNotification),
+	}
 	return nil
 }
 
 // EachLoop is called at the start of every munge loop
-func (CloseStale) EachLoop() error { return nil }
+func (*CloseStale) EachLoop() error { return nil }
 
 // AddFlags will add any request flags to the cobra `cmd`
-func (CloseStale) AddFlags(cmd *cobra.Command, config *github.Config) {}
+func (s *CloseStale) AddFlags(cmd *cobra.Command, config *github.Config) {
+	s.labelStalePeriods = labelPeriods{}
+
+	cmd.Flags().DurationVar(&s.stalePeriod, "stale-period", defaultStalePeriod, "Close the PR/Issue if there has been no human interaction for this long")
+	cmd.Flags().DurationVar(&s.startWarning, "stale-warning", defaultStartWarning, "Start warning this long before an object would be closed for staleness")
+	cmd.Flags().Var(s.labelStalePeriods, "stale-label-period", "Override the stale period for objects with <label>, e.g. --stale-label-period=kind/flake=30; a period of 0 exempts the label from staleness. May be repeated.")
+	cmd.Flags().StringSliceVar(&s.keepOpenLabels, "stale-keep-open-label", []string{defaultKeepOpenLabel}, "Labels which, if present, keep an object open forever")
+	cmd.Flags().StringSliceVar(&s.includeLabels, "stale-include-label", []string{defaultIncludeLabel}, "Labels which make a non-PR Issue eligible for staleness")
+	cmd.Flags().StringSliceVar(&s.excludeLabels, "stale-exclude-label", []string{}, "Labels which exempt an object from staleness")
+	cmd.Flags().BoolVar(&s.escalateToOwners, "stale-escalate-to-owners", false, "Before closing a stale PR/Issue, ping file/dir owners (PR) or assignees (Issue) and give them an extra grace period to respond")
+	cmd.Flags().BoolVar(&s.dryRun, "stale-dry-run", false, "Compute and audit-log what close-stale would do, without writing/deleting comments or opening/closing anything")
+}
 
 func findLastHumanPullRequestUpdate(obj *github.MungeObject) (*time.Time, bool) {
 	pr, ok := obj.GetPR()
@@ -187,134 +392,276 @@ func findLastModificationTime(obj *github.MungeObject) (*time.Time, bool) {
 	return lastModif, true
 }
 
-// Find the last warning comment that the bot has posted.
-// It can return an empty comment if it fails to find one, even if there are no errors.
-func findLatestWarningComment(obj *github.MungeObject) (*githubapi.IssueComment, bool) {
-	var lastFoundComment *githubapi.IssueComment
+func durationToDays(duration time.Duration) string {
+	days := duration / day
+	dayString := "days"
+	if days == 1 || days == -1 {
+		dayString = "day"
+	}
+	return fmt.Sprintf("%d %s", days, dayString)
+}
 
-	comments, ok := obj.ListComments()
-	if !ok {
-		return nil, ok
+// hasAnyLabel returns true if obj carries any of the given labels.
+func hasAnyLabel(obj *github.MungeObject, labels []string) bool {
+	for _, label := range labels {
+		if obj.HasLabel(label) {
+			return true
+		}
 	}
+	return false
+}
 
-	for i := range comments {
-		comment := comments[i]
-		if !validComment(comment) {
+// stalePeriodForLabels is stalePeriodFor's pure core: given a default period,
+// the configured per-label overrides, and a predicate reporting whether a
+// given label is present, decide the effective stale period. Separating it
+// from obj.HasLabel makes it unit-testable without a *github.MungeObject.
+//
+// A matching label is a real override, not just a floor on the default: if
+// any configured label matches, the effective period is the minimum of the
+// *matching* overrides alone (the default is ignored entirely), so
+// --stale-label-period can lengthen the period just as well as shorten it.
+func stalePeriodForLabels(defaultPeriod time.Duration, labelPeriods labelPeriods, hasLabel func(label string) bool) (period time.Duration, ok bool) {
+	matched := false
+	for label, labelPeriod := range labelPeriods {
+		if !hasLabel(label) {
 			continue
 		}
-		if !mergeBotComment(comment) {
-			continue
+		if labelPeriod == neverStale {
+			return 0, false
 		}
-
-		if !warningCommentRE.MatchString(*comment.Body) {
-			continue
+		if !matched || labelPeriod < period {
+			period = labelPeriod
 		}
+		matched = true
+	}
+	if !matched {
+		return defaultPeriod, true
+	}
+	return period, true
+}
 
-		if lastFoundComment == nil || lastFoundComment.CreatedAt.Before(*comment.UpdatedAt) {
-			if lastFoundComment != nil {
-				obj.DeleteComment(lastFoundComment)
-			}
-			lastFoundComment = comment
-		}
+// stalePeriodFor returns the stale period that applies to obj, honoring any
+// per-label override from --stale-label-period. ok is false if a matching
+// label exempts obj from staleness entirely (neverStale).
+func (s *CloseStale) stalePeriodFor(obj *github.MungeObject) (period time.Duration, ok bool) {
+	return stalePeriodForLabels(s.stalePeriod, s.labelStalePeriods, obj.HasLabel)
+}
+
+func objType(obj *github.MungeObject) string {
+	if obj.IsPR() {
+		return "PR"
 	}
+	return "Issue"
+}
 
-	return lastFoundComment, true
+// staleKind returns the first of s.includeLabels present on obj, falling
+// back to "pr" for PRs (which aren't gated by includeLabels) or "unknown",
+// for use as the "kind" metrics label.
+func (s *CloseStale) staleKind(obj *github.MungeObject) string {
+	for _, label := range s.includeLabels {
+		if obj.HasLabel(label) {
+			return label
+		}
+	}
+	if obj.IsPR() {
+		return "pr"
+	}
+	return "unknown"
 }
 
-func durationToDays(duration time.Duration) string {
-	days := duration / day
-	dayString := "days"
-	if days == 1 || days == -1 {
-		dayString = "day"
+func (s *CloseStale) closeObj(obj *github.MungeObject, lastModif time.Time, inactiveFor time.Duration) {
+	rawMention := mungerutil.GetIssueUsers(obj.Issue).AllUsers().Mention().Join()
+	mention := rawMention
+	if mention != "" {
+		mention = "cc " + mention + "\n"
+	}
+
+	s.audit(obj, lastModif, "close", nil, rawMention)
+
+	s.warningPinger.Clear(obj)
+	s.escalationPinger.Clear(obj)
+
+	t := objType(obj)
+	kind := s.staleKind(obj)
+	if !s.dryRun {
+		// Carry kind in the marker's Arguments so a later "/reopen" (handled
+		// by the separate ReopenStale munger) reports stale_reopened_total
+		// under the same kind as stale_closed_total did, instead of
+		// re-deriving it with a different, possibly differently-configured
+		// heuristic.
+		marker := mungerutil.Notification{Name: s.closedPinger.Notification, Arguments: kind}
+		obj.WriteComment(marker.String() + "\n" + fmt.Sprintf(closingComment, t, durationToDays(inactiveFor), t, t, mention))
+
+		if obj.IsPR() {
+			obj.ClosePR()
+		} else {
+			obj.CloseIssuef("")
+		}
+
+		staleClosedTotal.WithLabelValues(kind, t).Inc()
 	}
-	return fmt.Sprintf("%d %s", days, dayString)
 }
 
-func closeObj(obj *github.MungeObject, inactiveFor time.Duration) {
+// warningCommentBody renders the human-readable part of a stale-warning
+// ping; pingCount is unused today but kept so future pings can, e.g.,
+// mention how many times the object has already been warned.
+func warningCommentBody(obj *github.MungeObject, inactiveFor, closeIn time.Duration, pingCount int) string {
 	mention := mungerutil.GetIssueUsers(obj.Issue).AllUsers().Mention().Join()
 	if mention != "" {
 		mention = "cc " + mention + "\n"
 	}
 
-	comment, ok := findLatestWarningComment(obj)
-	if !ok {
-		return
-	}
-	if comment != nil {
-		obj.DeleteComment(comment)
+	closeDate := time.Now().Add(closeIn).Format("Jan 2, 2006")
+
+	return fmt.Sprintf(
+		warningComment,
+		objType(obj),
+		durationToDays(inactiveFor),
+		durationToDays(closeIn),
+		closeDate,
+		mention,
+	)
+}
+
+// dedupLogins flattens groups of logins into a single list, dropping empty
+// entries and later duplicates while preserving first-seen order. It's pure
+// -- no GitHub API involved -- so it's unit-testable on its own.
+func dedupLogins(groups ...[]string) []string {
+	seen := map[string]bool{}
+	var logins []string
+	for _, group := range groups {
+		for _, login := range group {
+			if login == "" || seen[login] {
+				continue
+			}
+			seen[login] = true
+			logins = append(logins, login)
+		}
 	}
+	return logins
+}
 
-	var objType string
+// escalationMentions returns the "@login" mentions for whoever should be
+// escalated to before obj is closed: the owners of the files it touches if
+// it's a PR, or its assignees if it's an Issue.
+func (s *CloseStale) escalationMentions(obj *github.MungeObject) string {
+	var groups [][]string
 
 	if obj.IsPR() {
-		objType = "PR"
+		files, ok := obj.ListFiles()
+		if !ok {
+			return ""
+		}
+		for _, f := range files {
+			if f.Filename == nil {
+				continue
+			}
+			groups = append(groups, s.features.Repos.Assignees(filepath.Dir(*f.Filename)))
+		}
 	} else {
-		objType = "Issue"
+		var assignees []string
+		for _, user := range obj.Issue.Assignees {
+			if user != nil && user.Login != nil {
+				assignees = append(assignees, *user.Login)
+			}
+		}
+		groups = append(groups, assignees)
 	}
 
-	obj.WriteComment(fmt.Sprintf(closingComment, objType, durationToDays(inactiveFor), objType, objType, mention))
+	logins := dedupLogins(groups...)
+	if len(logins) == 0 {
+		return ""
+	}
+	mentions := make([]string, len(logins))
+	for i, login := range logins {
+		mentions[i] = "@" + login
+	}
+	return strings.Join(mentions, " ")
+}
 
-	if obj.IsPR() {
-		obj.ClosePR()
-	} else {
-		obj.CloseIssuef("")
+func escalationCommentBody(obj *github.MungeObject, inactiveFor time.Duration, mentions string) string {
+	cc := mentions
+	if cc != "" {
+		cc = "cc " + cc + "\n"
 	}
+	t := objType(obj)
+	closeDate := time.Now().Add(escalationGracePeriod).Format("Jan 2, 2006")
+	return fmt.Sprintf(escalationComment, t, durationToDays(inactiveFor), cc, t, durationToDays(escalationGracePeriod), closeDate)
 }
 
-func postWarningComment(obj *github.MungeObject, inactiveFor time.Duration, closeIn time.Duration) {
-	mention := mungerutil.GetIssueUsers(obj.Issue).AllUsers().Mention().Join()
-	if mention != "" {
-		mention = "cc " + mention + "\n"
+// escalateOrClose runs the second, owner/assignee-facing warning stage: the
+// first time a stale object would be closed, it pings owners/assignees
+// instead and gives them escalationGracePeriod to respond. Only once that
+// also goes unanswered does it fall through to closeObj.
+func (s *CloseStale) escalateOrClose(obj *github.MungeObject, lastModif time.Time, inactiveFor time.Duration) {
+	comment, expired, ok := s.escalationPinger.Status(obj, lastModif)
+	if !ok {
+		return
 	}
 
-	closeDate := time.Now().Add(closeIn).Format("Jan 2, 2006")
+	if comment == nil {
+		mentions := s.escalationMentions(obj)
+		plannedClose := time.Now().Add(escalationGracePeriod)
+		s.audit(obj, lastModif, "escalate", &plannedClose, mentions)
 
-	var objType string
+		if !s.dryRun {
+			marker := mungerutil.Notification{Name: s.escalationPinger.Notification}
+			obj.WriteComment(marker.String() + "\n" + escalationCommentBody(obj, inactiveFor, mentions))
+		}
+		return
+	}
 
-	if obj.IsPR() {
-		objType = "PR"
-	} else {
-		objType = "Issue"
+	if !expired {
+		// Still within the grace period; give owners/assignees more time.
+		return
 	}
 
-	obj.WriteComment(fmt.Sprintf(
-		warningComment,
-		objType,
-		durationToDays(inactiveFor),
-		durationToDays(closeIn),
-		closeDate,
-		mention,
-	))
+	// Escalated, and the grace period elapsed with no response.
+	s.escalationPinger.Clear(obj)
+	s.closeObj(obj, lastModif, inactiveFor)
 }
 
-func checkAndWarn(obj *github.MungeObject, inactiveFor time.Duration, closeIn time.Duration) {
+func (s *CloseStale) checkAndWarn(obj *github.MungeObject, lastModif time.Time, inactiveFor, closeIn time.Duration) {
 	if closeIn < day {
 		// We are going to close the PR/Issue in less than a day. Too late to warn
 		return
 	}
-	comment, ok := findLatestWarningComment(obj)
-	if !ok {
-		return
-	}
-	if comment == nil {
-		// We don't already have the comment. Post it
-		postWarningComment(obj, inactiveFor, closeIn)
-	} else if time.Since(*comment.UpdatedAt) > remindWarning {
-		// It's time to warn again
-		obj.DeleteComment(comment)
-		postWarningComment(obj, inactiveFor, closeIn)
-	} else {
-		// We already have a warning, and it's not expired. Do nothing
+
+	plannedClose := time.Now().Add(closeIn)
+	mention := mungerutil.GetIssueUsers(obj.Issue).AllUsers().Mention().Join()
+	s.audit(obj, lastModif, "warn", &plannedClose, mention)
+
+	_, posted, _ := s.warningPinger.Ping(obj, lastModif, func(pingCount int) string {
+		return warningCommentBody(obj, inactiveFor, closeIn, pingCount)
+	})
+	if posted && !s.dryRun {
+		staleWarnedTotal.WithLabelValues(s.staleKind(obj), objType(obj)).Inc()
 	}
 }
 
+// syncDryRun propagates --stale-dry-run to the pingers, which perform the
+// actual comment writes/deletes on CloseStale's behalf.
+func (s *CloseStale) syncDryRun() {
+	s.warningPinger.DryRun = s.dryRun
+	s.escalationPinger.DryRun = s.dryRun
+	s.closedPinger.DryRun = s.dryRun
+}
+
 // Munge is the workhorse that will actually close the PRs/Issues
-func (CloseStale) Munge(obj *github.MungeObject) {
-	if !obj.IsPR() && !obj.HasLabel(kindFlakeLabel) {
+func (s *CloseStale) Munge(obj *github.MungeObject) {
+	s.syncDryRun()
+
+	if !obj.IsPR() && !hasAnyLabel(obj, s.includeLabels) {
 		return
 	}
 
-	if obj.HasLabel(keepOpenLabel) {
+	if hasAnyLabel(obj, s.keepOpenLabels) || hasAnyLabel(obj, s.excludeLabels) {
+		return
+	}
+
+	stalePeriod, ok := s.stalePeriodFor(obj)
+	if !ok {
+		// A matching --stale-label-period exempts this object entirely.
 		return
 	}
 
@@ -326,19 +673,22 @@ func (CloseStale) Munge(obj *github.MungeObject) {
 	closeIn := -time.Since(lastModif.Add(stalePeriod))
 	inactiveFor := time.Since(*lastModif)
 	if closeIn <= 0 {
-		closeObj(obj, inactiveFor)
-	} else if closeIn <= startWarning {
-		checkAndWarn(obj, inactiveFor, closeIn)
-	} else {
-		// PR/Issue is active. Remove previous potential warning
-		comment, ok := findLatestWarningComment(obj)
-		if comment != nil && ok {
-			obj.DeleteComment(comment)
+		if s.escalateToOwners {
+			s.escalateOrClose(obj, *lastModif, inactiveFor)
+		} else {
+			s.closeObj(obj, *lastModif, inactiveFor)
 		}
+	} else if closeIn <= s.startWarning {
+		s.checkAndWarn(obj, *lastModif, inactiveFor, closeIn)
+	} else {
+		// PR/Issue is active. Remove previous potential warning/escalation
+		s.audit(obj, *lastModif, "clear", nil, "")
+		s.warningPinger.Clear(obj)
+		s.escalationPinger.Clear(obj)
 	}
 }
 
-func (CloseStale) isStaleComment(obj *github.MungeObject, comment *githubapi.IssueComment) bool {
+func (*CloseStale) isStaleComment(obj *github.MungeObject, comment *githubapi.IssueComment) bool {
 	if !mergeBotComment(comment) {
 		return false
 	}
@@ -351,6 +701,6 @@ func (CloseStale) isStaleComment(obj *github.MungeObject, comment *githubapi.Iss
 }
 
 // StaleComments returns a slice of stale comments
-func (s CloseStale) StaleComments(obj *github.MungeObject, comments []*githubapi.IssueComment) []*githubapi.IssueComment {
+func (s *CloseStale) StaleComments(obj *github.MungeObject, comments []*githubapi.IssueComment) []*githubapi.IssueComment {
 	return forEachCommentTest(obj, comments, s.isStaleComment)
 }
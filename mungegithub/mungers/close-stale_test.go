@@ -0,0 +1,199 @@
+/*
+Copyright 2016 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mungers
+
+import (
+	"testing"
+	"time"
+)
+
+func TestLabelPeriodsSet(t *testing.T) {
+	tests := []struct {
+		value   string
+		wantErr bool
+		want    time.Duration
+	}{
+		{value: "kind/flake=30", want: 30 * day},
+		{value: "priority/critical=0", want: 0},
+		{value: "missing-equals", wantErr: true},
+		{value: "kind/flake=not-a-number", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		l := labelPeriods{}
+		err := l.Set(tt.value)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("Set(%q): expected error, got none", tt.value)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("Set(%q): unexpected error: %v", tt.value, err)
+			continue
+		}
+		pieces := len(l)
+		if pieces != 1 {
+			t.Errorf("Set(%q): expected exactly one label recorded, got %d", tt.value, pieces)
+		}
+		for _, period := range l {
+			if period != tt.want {
+				t.Errorf("Set(%q): got period %v, want %v", tt.value, period, tt.want)
+			}
+		}
+	}
+}
+
+func TestLabelPeriodsString(t *testing.T) {
+	l := labelPeriods{"kind/flake": 30 * day}
+	got := l.String()
+	want := "kind/flake=30"
+	if got != want {
+		t.Errorf("String() = %q, want %q", got, want)
+	}
+}
+
+func TestStalePeriodForLabels(t *testing.T) {
+	hasLabel := func(labels ...string) func(string) bool {
+		set := map[string]bool{}
+		for _, l := range labels {
+			set[l] = true
+		}
+		return func(label string) bool { return set[label] }
+	}
+
+	tests := []struct {
+		name          string
+		defaultPeriod time.Duration
+		labelPeriods  labelPeriods
+		labels        []string
+		wantPeriod    time.Duration
+		wantOK        bool
+	}{
+		{
+			name:          "no matching label override",
+			defaultPeriod: 90 * day,
+			labelPeriods:  labelPeriods{"kind/flake": 30 * day},
+			labels:        nil,
+			wantPeriod:    90 * day,
+			wantOK:        true,
+		},
+		{
+			name:          "shorter override wins",
+			defaultPeriod: 90 * day,
+			labelPeriods:  labelPeriods{"kind/flake": 30 * day},
+			labels:        []string{"kind/flake"},
+			wantPeriod:    30 * day,
+			wantOK:        true,
+		},
+		{
+			name:          "most restrictive of several overrides wins",
+			defaultPeriod: 90 * day,
+			labelPeriods:  labelPeriods{"kind/flake": 30 * day, "priority/important-soon": 10 * day},
+			labels:        []string{"kind/flake", "priority/important-soon"},
+			wantPeriod:    10 * day,
+			wantOK:        true,
+		},
+		{
+			name:          "neverStale label exempts regardless of other overrides",
+			defaultPeriod: 90 * day,
+			labelPeriods:  labelPeriods{"kind/flake": 30 * day, "priority/critical": neverStale},
+			labels:        []string{"kind/flake", "priority/critical"},
+			wantPeriod:    0,
+			wantOK:        false,
+		},
+		{
+			name:          "override longer than default still applies",
+			defaultPeriod: 90 * day,
+			labelPeriods:  labelPeriods{"long-review": 365 * day},
+			labels:        []string{"long-review"},
+			wantPeriod:    365 * day,
+			wantOK:        true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			period, ok := stalePeriodForLabels(tt.defaultPeriod, tt.labelPeriods, hasLabel(tt.labels...))
+			if ok != tt.wantOK {
+				t.Errorf("ok = %v, want %v", ok, tt.wantOK)
+			}
+			if period != tt.wantPeriod {
+				t.Errorf("period = %v, want %v", period, tt.wantPeriod)
+			}
+		})
+	}
+}
+
+func TestMentionLogins(t *testing.T) {
+	tests := []struct {
+		mentions string
+		want     []string
+	}{
+		{mentions: "", want: nil},
+		{mentions: "@alice", want: []string{"alice"}},
+		{mentions: "@alice @bob", want: []string{"alice", "bob"}},
+	}
+
+	for _, tt := range tests {
+		got := mentionLogins(tt.mentions)
+		if len(got) != len(tt.want) {
+			t.Errorf("mentionLogins(%q) = %v, want %v", tt.mentions, got, tt.want)
+			continue
+		}
+		for i := range got {
+			if got[i] != tt.want[i] {
+				t.Errorf("mentionLogins(%q) = %v, want %v", tt.mentions, got, tt.want)
+				break
+			}
+		}
+	}
+}
+
+func TestDedupLogins(t *testing.T) {
+	tests := []struct {
+		name   string
+		groups [][]string
+		want   []string
+	}{
+		{name: "empty", groups: nil, want: nil},
+		{
+			name:   "drops empty logins",
+			groups: [][]string{{"", "alice", ""}},
+			want:   []string{"alice"},
+		},
+		{
+			name:   "dedups across groups, keeps first-seen order",
+			groups: [][]string{{"alice", "bob"}, {"bob", "carol"}},
+			want:   []string{"alice", "bob", "carol"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := dedupLogins(tt.groups...)
+			if len(got) != len(tt.want) {
+				t.Fatalf("dedupLogins() = %v, want %v", got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("dedupLogins()[%d] = %q, want %q", i, got[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
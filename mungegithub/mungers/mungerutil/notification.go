@@ -0,0 +1,86 @@
+/*
+Copyright 2016 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mungerutil
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	githubapi "github.com/google/go-github/github"
+)
+
+// NotificationName identifies a particular kind of machine-readable marker a
+// munger embeds in a bot comment, so the munger can reliably find its own
+// comments again later without matching on the human-readable prose.
+type NotificationName string
+
+// MungerNotificationName builds the NotificationName a munger should use to
+// tag its own comments, e.g. MungerNotificationName("close-stale-warning").
+func MungerNotificationName(name string) NotificationName {
+	return NotificationName(name)
+}
+
+const (
+	notificationPrefix = "<!-- MUNGER-NOTIFICATION="
+	notificationSuffix = " -->"
+)
+
+// Notification is a marker a munger can embed at the top of a bot comment,
+// ahead of the human-readable body, and later find again with
+// ParseNotification. Arguments is free-form, munger-defined state (e.g. a
+// ping count) carried alongside the marker.
+type Notification struct {
+	Name      NotificationName
+	Arguments string
+}
+
+// String renders the notification as an HTML-comment marker meant to be
+// prepended to the human-readable body of a bot comment.
+func (n Notification) String() string {
+	raw, err := json.Marshal(n)
+	if err != nil {
+		// Notification only ever holds strings; this should never happen.
+		return ""
+	}
+	return fmt.Sprintf("%s%s%s", notificationPrefix, string(raw), notificationSuffix)
+}
+
+// ParseNotification extracts the Notification marker from a comment body, if
+// any. It returns false if the comment does not carry one.
+func ParseNotification(comment *githubapi.IssueComment) (*Notification, bool) {
+	if comment == nil || comment.Body == nil {
+		return nil, false
+	}
+
+	body := *comment.Body
+	start := strings.Index(body, notificationPrefix)
+	if start == -1 {
+		return nil, false
+	}
+	rest := body[start+len(notificationPrefix):]
+	end := strings.Index(rest, notificationSuffix)
+	if end == -1 {
+		return nil, false
+	}
+
+	var n Notification
+	if err := json.Unmarshal([]byte(rest[:end]), &n); err != nil {
+		return nil, false
+	}
+	return &n, true
+}
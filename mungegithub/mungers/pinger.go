@@ -0,0 +1,176 @@
+/*
+Copyright 2016 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mungers
+
+import (
+	"strconv"
+	"time"
+
+	"k8s.io/test-infra/mungegithub/github"
+	"k8s.io/test-infra/mungegithub/mungers/mungerutil"
+
+	githubapi "github.com/google/go-github/github"
+)
+
+// Pinger implements a generic "ping, then act" workflow shared by mungers
+// that need to nag a PR/Issue at most once per Period: post a reminder
+// comment tagged with Notification, wait for Period to elapse, and repeat --
+// starting over whenever a human has acted more recently than the last ping.
+//
+// Finding the latest ping relies on the Notification marker embedded in the
+// comment (see mungerutil.Notification), not on matching the comment's
+// prose, so the reminder text is free to change without breaking lookup.
+type Pinger struct {
+	// Notification tags comments this Pinger posts, and is used to find them
+	// again later.
+	Notification mungerutil.NotificationName
+	// Period is how long a ping stays valid before it's time to ping again.
+	Period time.Duration
+	// DryRun, when set, makes Ping/Clear report what they would do without
+	// actually writing or deleting any comment.
+	DryRun bool
+}
+
+// latestNotification picks, among already-fetched comments, the most recent
+// one carrying notification's marker, along with any older duplicates
+// carrying the same marker (which the caller should clean up). It does no
+// I/O itself, which makes it (and pingExpiry below) unit-testable without a
+// *github.MungeObject.
+func latestNotification(comments []*githubapi.IssueComment, notification mungerutil.NotificationName) (current *githubapi.IssueComment, stale []*githubapi.IssueComment) {
+	for i := range comments {
+		comment := comments[i]
+		if !validComment(comment) || !mergeBotComment(comment) {
+			continue
+		}
+		n, ok := mungerutil.ParseNotification(comment)
+		if !ok || n.Name != notification {
+			continue
+		}
+
+		if current == nil || current.CreatedAt.Before(*comment.UpdatedAt) {
+			if current != nil {
+				stale = append(stale, current)
+			}
+			current = comment
+		} else {
+			stale = append(stale, comment)
+		}
+	}
+	return current, stale
+}
+
+// pingExpiry decides what to do with a found ping comment: supersededByHuman
+// reports that a human has acted more recently than the ping was posted, so
+// it no longer counts at all; expired reports that Period has elapsed since
+// it was last refreshed, so it's time to act again. comment == nil yields
+// neither.
+func pingExpiry(comment *githubapi.IssueComment, sinceHuman time.Time, period time.Duration) (supersededByHuman, expired bool) {
+	if comment == nil {
+		return false, false
+	}
+	if comment.CreatedAt != nil && comment.CreatedAt.Before(sinceHuman) {
+		return true, false
+	}
+	return false, comment.UpdatedAt != nil && time.Since(*comment.UpdatedAt) >= period
+}
+
+// LatestNotification returns the most recent bot comment carrying p's
+// Notification marker, deleting any older duplicates it encounters along the
+// way. In DryRun mode, duplicates are left in place.
+func (p Pinger) LatestNotification(obj *github.MungeObject) (*githubapi.IssueComment, bool) {
+	comments, ok := obj.ListComments()
+	if !ok {
+		return nil, false
+	}
+
+	current, stale := latestNotification(comments, p.Notification)
+	if !p.DryRun {
+		for _, comment := range stale {
+			obj.DeleteComment(comment)
+		}
+	}
+	return current, true
+}
+
+// Status reports the outstanding ping for obj, if any, and whether it has
+// expired (Period elapsed since it was last refreshed). A ping that predates
+// sinceHuman -- i.e. a human has since acted -- is treated as if there were
+// no ping at all, and is cleaned up unless DryRun. Callers that need to
+// decide "is there a live ping, and is it time to act again" (Ping,
+// escalateOrClose) should use this instead of re-deriving the same decision.
+func (p Pinger) Status(obj *github.MungeObject, sinceHuman time.Time) (comment *githubapi.IssueComment, expired bool, ok bool) {
+	comment, ok = p.LatestNotification(obj)
+	if !ok {
+		return nil, false, false
+	}
+
+	supersededByHuman, expired := pingExpiry(comment, sinceHuman, p.Period)
+	if supersededByHuman {
+		if !p.DryRun {
+			obj.DeleteComment(comment)
+		}
+		return nil, false, true
+	}
+	return comment, expired, true
+}
+
+// Ping posts a new notification comment -- built by renderComment from the
+// ping count -- unless one already posted since sinceHuman is still within
+// Period. It returns the ping count in effect after the call (1 the first
+// time, 2 the next, and so on) and whether a new comment was actually
+// posted, so callers can tell a fresh ping from a no-op.
+func (p Pinger) Ping(obj *github.MungeObject, sinceHuman time.Time, renderComment func(pingCount int) string) (pingCount int, posted bool, ok bool) {
+	comment, expired, ok := p.Status(obj, sinceHuman)
+	if !ok {
+		return 0, false, false
+	}
+
+	pingCount = 1
+	if comment != nil {
+		notification, _ := mungerutil.ParseNotification(comment)
+		if n, err := strconv.Atoi(notification.Arguments); err == nil {
+			pingCount = n + 1
+		}
+		if !expired {
+			// Still within Period; leave the existing ping alone.
+			return pingCount - 1, false, true
+		}
+		if !p.DryRun {
+			obj.DeleteComment(comment)
+		}
+	}
+
+	if p.DryRun {
+		return pingCount, true, true
+	}
+
+	marker := mungerutil.Notification{Name: p.Notification, Arguments: strconv.Itoa(pingCount)}
+	obj.WriteComment(marker.String() + "\n" + renderComment(pingCount))
+	return pingCount, true, true
+}
+
+// Clear removes any outstanding notification comment this Pinger posted,
+// e.g. once the object is active again.
+func (p Pinger) Clear(obj *github.MungeObject) {
+	if p.DryRun {
+		return
+	}
+	comment, ok := p.LatestNotification(obj)
+	if ok && comment != nil {
+		obj.DeleteComment(comment)
+	}
+}
@@ -0,0 +1,114 @@
+/*
+Copyright 2016 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mungers
+
+import (
+	"testing"
+	"time"
+
+	"k8s.io/test-infra/mungegithub/mungers/mungerutil"
+
+	githubapi "github.com/google/go-github/github"
+)
+
+func notificationComment(name mungerutil.NotificationName, args string, created, updated time.Time) *githubapi.IssueComment {
+	body := mungerutil.Notification{Name: name, Arguments: args}.String() + "\nsome reminder text"
+	user := botName
+	return &githubapi.IssueComment{
+		Body:      &body,
+		User:      &githubapi.User{Login: &user},
+		CreatedAt: &created,
+		UpdatedAt: &updated,
+	}
+}
+
+func TestLatestNotification(t *testing.T) {
+	const name = mungerutil.NotificationName("test-notification")
+	other := mungerutil.NotificationName("other-notification")
+
+	base := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	older := notificationComment(name, "1", base, base)
+	newer := notificationComment(name, "2", base.Add(time.Hour), base.Add(time.Hour))
+	wrongKind := notificationComment(other, "1", base.Add(2*time.Hour), base.Add(2*time.Hour))
+
+	current, stale := latestNotification([]*githubapi.IssueComment{older, newer, wrongKind}, name)
+
+	if current != newer {
+		t.Errorf("latestNotification returned %v, want the newest matching comment", current)
+	}
+	if len(stale) != 1 || stale[0] != older {
+		t.Errorf("latestNotification stale = %v, want [older]", stale)
+	}
+}
+
+func TestPingExpiry(t *testing.T) {
+	const period = 30 * day
+	base := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name           string
+		comment        *githubapi.IssueComment
+		sinceHuman     time.Time
+		wantSuperseded bool
+		wantExpired    bool
+	}{
+		{
+			name:    "no comment",
+			comment: nil,
+		},
+		{
+			name: "posted before a later human comment is superseded",
+			comment: &githubapi.IssueComment{
+				CreatedAt: timePtr(base),
+				UpdatedAt: timePtr(base),
+			},
+			sinceHuman:     base.Add(time.Hour),
+			wantSuperseded: true,
+		},
+		{
+			name: "fresh ping within period",
+			comment: &githubapi.IssueComment{
+				CreatedAt: timePtr(time.Now()),
+				UpdatedAt: timePtr(time.Now()),
+			},
+			sinceHuman: base,
+		},
+		{
+			name: "ping older than period has expired",
+			comment: &githubapi.IssueComment{
+				CreatedAt: timePtr(base),
+				UpdatedAt: timePtr(base),
+			},
+			sinceHuman:  base.Add(-time.Hour),
+			wantExpired: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			superseded, expired := pingExpiry(tt.comment, tt.sinceHuman, period)
+			if superseded != tt.wantSuperseded {
+				t.Errorf("supersededByHuman = %v, want %v", superseded, tt.wantSuperseded)
+			}
+			if expired != tt.wantExpired {
+				t.Errorf("expired = %v, want %v", expired, tt.wantExpired)
+			}
+		})
+	}
+}
+
+func timePtr(t time.Time) *time.Time { return &t }
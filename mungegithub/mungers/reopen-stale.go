@@ -0,0 +1,188 @@
+/*
+Copyright 2016 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mungers
+
+import (
+	"regexp"
+	"strings"
+	"time"
+
+	"k8s.io/test-infra/mungegithub/features"
+	"k8s.io/test-infra/mungegithub/github"
+	"k8s.io/test-infra/mungegithub/mungers/mungerutil"
+
+	githubapi "github.com/google/go-github/github"
+	"github.com/golang/glog"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/spf13/cobra"
+)
+
+const (
+	// defaultReopenWindow is how long after an auto-close a human "/reopen"
+	// comment is still honored.
+	defaultReopenWindow = 30 * day
+)
+
+// reopenCommandRE matches a "/reopen" slash-command on its own line, the way
+// slash-commands are conventionally parsed elsewhere -- a bare substring
+// match would also fire on prose like "please don't /reopen this yet".
+var reopenCommandRE = regexp.MustCompile(`(?m)^/reopen\s*$`)
+
+var staleReopenedTotal = prometheus.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: "stale_reopened_total",
+		Help: "Number of PRs/Issues close-stale has reopened after a human '/reopen', by kind and object type",
+	},
+	[]string{"kind", "objtype"},
+)
+
+func init() {
+	prometheus.MustRegister(staleReopenedTotal)
+}
+
+// ReopenStale reopens a PR/Issue that CloseStale auto-closed, if a human
+// comments a bare "/reopen" soon afterward.
+//
+// It's a separate munger, not a branch inside CloseStale.Munge, because the
+// munge loop only ever re-visits currently-open objects: once closeObj
+// closes something, Munge
+// won't run on it again to notice a later "/reopen". Run this munger
+// against a query that includes recently-closed PRs/Issues (e.g. a
+// `--pr-mungers=reopen-stale` invocation configured with an `is:closed`
+// search) alongside close-stale's own open-objects query.
+type ReopenStale struct {
+	reopenWindow time.Duration
+	pinger       Pinger
+
+	// dryRun, when set, makes Munge log what it would do without actually
+	// reopening anything or clearing the close marker.
+	dryRun bool
+}
+
+func init() {
+	RegisterMungerOrDie(&ReopenStale{})
+}
+
+// Name is the name usable in --pr-mungers
+func (*ReopenStale) Name() string { return "reopen-stale" }
+
+// RequiredFeatures is a slice of 'features' that must be provided
+func (*ReopenStale) RequiredFeatures() []string { return []string{} }
+
+// Initialize will initialize the munger
+func (r *ReopenStale) Initialize(config *github.Config, features *features.Features) error {
+	r.pinger = Pinger{
+		Notification: mungerutil.MungerNotificationName(closeStaleClosedNotification),
+	}
+	return nil
+}
+
+// EachLoop is called at the start of every munge loop
+func (*ReopenStale) EachLoop() error { return nil }
+
+// AddFlags will add any request flags to the cobra `cmd`
+func (r *ReopenStale) AddFlags(cmd *cobra.Command, config *github.Config) {
+	cmd.Flags().DurationVar(&r.reopenWindow, "stale-reopen-window", defaultReopenWindow, "Honor a human '/reopen' comment for this long after close-stale auto-closes a PR/Issue")
+	cmd.Flags().BoolVar(&r.dryRun, "reopen-stale-dry-run", false, "Log what reopen-stale would do, without reopening anything")
+}
+
+// reopenKindFromLabels is reopenKind's pure core: given the kind carried by
+// the close marker (if any), obj's labels, and whether obj is a PR, decide
+// the "kind" metrics label. Separating it from *github.MungeObject/
+// mungerutil.ParseNotification makes it unit-testable on its own.
+func reopenKindFromLabels(carriedKind string, labels []*githubapi.Label, isPR bool) string {
+	if carriedKind != "" {
+		return carriedKind
+	}
+	for _, label := range labels {
+		if label.Name != nil && strings.HasPrefix(*label.Name, "kind/") {
+			return *label.Name
+		}
+	}
+	if isPR {
+		return "pr"
+	}
+	return "unknown"
+}
+
+// reopenKind recovers the "kind" metrics label CloseStale.closeObj recorded
+// in the close-stale-closed marker's Arguments when it closed obj, so
+// stale_reopened_total lines up with the stale_closed_total entry it
+// reverses instead of being re-derived by a heuristic that may disagree
+// with CloseStale's configured --stale-include-label set. Falls back to a
+// kind/*-label scan for markers posted before this field existed.
+func reopenKind(obj *github.MungeObject, closedComment *githubapi.IssueComment) string {
+	var carriedKind string
+	if notification, ok := mungerutil.ParseNotification(closedComment); ok {
+		carriedKind = notification.Arguments
+	}
+	return reopenKindFromLabels(carriedKind, obj.Issue.Labels, obj.IsPR())
+}
+
+// Munge reopens obj if it's closed, close-stale closed it within
+// reopenWindow, and a human has since commented a bare "/reopen".
+func (r *ReopenStale) Munge(obj *github.MungeObject) {
+	r.pinger.DryRun = r.dryRun
+
+	if obj.Issue.State == nil || *obj.Issue.State != "closed" {
+		return
+	}
+
+	closedComment, ok := r.pinger.LatestNotification(obj)
+	if !ok || closedComment == nil || closedComment.CreatedAt == nil {
+		return
+	}
+	if time.Since(*closedComment.CreatedAt) > r.reopenWindow {
+		return
+	}
+
+	comments, ok := obj.ListComments()
+	if !ok {
+		return
+	}
+	for i := range comments {
+		comment := comments[i]
+		if !validComment(comment) || mergeBotComment(comment) || jenkinsBotComment(comment) {
+			continue
+		}
+		if comment.CreatedAt == nil || comment.CreatedAt.Before(*closedComment.CreatedAt) {
+			continue
+		}
+		if comment.Body == nil || !reopenCommandRE.MatchString(*comment.Body) {
+			continue
+		}
+
+		t := objType(obj)
+		if r.dryRun {
+			number := 0
+			if obj.Issue.Number != nil {
+				number = *obj.Issue.Number
+			}
+			glog.Infof("reopen-stale: would reopen %s #%d (dry-run)", t, number)
+			return
+		}
+
+		r.pinger.Clear(obj)
+		if obj.IsPR() {
+			obj.ReopenPR()
+		} else {
+			obj.ReopenIssue()
+		}
+		staleReopenedTotal.WithLabelValues(reopenKind(obj, closedComment), t).Inc()
+		return
+	}
+}
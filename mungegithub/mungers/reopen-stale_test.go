@@ -0,0 +1,66 @@
+/*
+Copyright 2016 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mungers
+
+import (
+	"testing"
+
+	githubapi "github.com/google/go-github/github"
+)
+
+func TestReopenKindFromLabels(t *testing.T) {
+	kindLabel := "kind/flake"
+	otherLabel := "keep-open"
+
+	tests := []struct {
+		name        string
+		carriedKind string
+		labels      []*githubapi.Label
+		isPR        bool
+		want        string
+	}{
+		{
+			name:        "kind carried by the marker wins regardless of labels",
+			carriedKind: "needs-triage",
+			labels:      []*githubapi.Label{{Name: &kindLabel}},
+			want:        "needs-triage",
+		},
+		{
+			name:   "falls back to a kind/ label when nothing was carried",
+			labels: []*githubapi.Label{{Name: &otherLabel}, {Name: &kindLabel}},
+			want:   "kind/flake",
+		},
+		{
+			name: "falls back to pr when nothing else matches",
+			isPR: true,
+			want: "pr",
+		},
+		{
+			name: "falls back to unknown when nothing else matches",
+			want: "unknown",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := reopenKindFromLabels(tt.carriedKind, tt.labels, tt.isPR)
+			if got != tt.want {
+				t.Errorf("reopenKindFromLabels() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}